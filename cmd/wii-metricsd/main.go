@@ -0,0 +1,49 @@
+// Command wii-metricsd detects a connected wii balance board and exports
+// its weight, battery, and session telemetry as Prometheus metrics on
+// /metrics.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tectiv3/wii-scale/wiiboard"
+	"github.com/tectiv3/wii-scale/wiiboard/metrics"
+)
+
+func main() {
+	addr := flag.String("addr", ":9351", "address to serve /metrics on")
+	flag.Parse()
+
+	board := wiiboard.New(wiiboard.NewLogrusLogger(logrus.StandardLogger()))
+	if err := board.Detect(); err != nil {
+		log.Fatalf("couldn't detect board: %v", err)
+	}
+
+	collector := metrics.New()
+	board.AddHook(collector)
+
+	go pollBattery(board, collector)
+	go board.Listen()
+
+	http.Handle("/metrics", collector.Handler())
+	log.Printf("serving metrics on %s/metrics", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+func pollBattery(board interface{ Battery() (int, error) }, c *metrics.Collector) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		percent, err := board.Battery()
+		if err != nil {
+			log.Printf("battery: %v", err)
+			continue
+		}
+		c.SetBattery(percent)
+	}
+}
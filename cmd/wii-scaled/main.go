@@ -0,0 +1,63 @@
+// Command wii-scaled detects a connected wii balance board and serves it
+// as a BoardService over gRPC, listening on either a TCP address or a
+// Unix socket.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/tectiv3/wii-scale/wiiboard"
+	"github.com/tectiv3/wii-scale/wiiboard/rpc"
+)
+
+func main() {
+	network := flag.String("network", "tcp", `listener network: "tcp" or "unix"`)
+	addr := flag.String("addr", ":9352", "address to listen on (a socket path when -network=unix)")
+	boardID := flag.String("board-id", "", "identifier tagged onto every streamed Event and WeightSample")
+	flag.Parse()
+
+	board := wiiboard.New(wiiboard.NewLogrusLogger(logrus.StandardLogger()))
+	if err := board.Detect(); err != nil {
+		log.Fatalf("couldn't detect board: %v", err)
+	}
+
+	srv := rpc.NewServer(&board, *boardID)
+	go board.Listen()
+
+	if *network == "unix" {
+		// an unclean shutdown (kill -9, OOM, power loss) leaves the
+		// socket file behind; remove it so the next start can bind.
+		if err := os.Remove(*addr); err != nil && !os.IsNotExist(err) {
+			log.Fatalf("couldn't remove stale socket %s: %v", *addr, err)
+		}
+	}
+
+	lis, err := net.Listen(*network, *addr)
+	if err != nil {
+		log.Fatalf("couldn't listen on %s %s: %v", *network, *addr, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	rpc.RegisterBoardServiceServer(grpcServer, srv)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sig
+		log.Print("shutting down")
+		grpcServer.GracefulStop()
+	}()
+
+	log.Printf("serving BoardService on %s %s", *network, *addr)
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,16 @@
+package wiiboard
+
+// Backend abstracts the transport used to read decoded sensor Events from
+// a physical balance board and to query its battery level, so the rest of
+// the package doesn't care whether a board arrived over evdev or a direct
+// BlueZ HID connection.
+type Backend interface {
+	// Read blocks until the transport delivers data and returns the sensor
+	// Events decoded from it; the slice is empty when that data completed
+	// no sensor Event.
+	Read() ([]Event, error)
+	// Battery returns the board's last known battery level, in percent.
+	Battery() (int, error)
+	// Close releases the backend's underlying resources.
+	Close() error
+}
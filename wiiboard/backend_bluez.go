@@ -0,0 +1,233 @@
+//go:build linux
+
+package wiiboard
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	bluezService = "org.bluez"
+
+	// classRVLWBC01 is the Class-of-Device the Wii Balance Board reports
+	// over Bluetooth (major class "peripheral", minor class "joystick").
+	classRVLWBC01 = 0x002504
+
+	l2capPSMControl   = 0x11
+	l2capPSMInterrupt = 0x13
+
+	reportInputData = 0x32 // input report: 4 calibrated 16-bit sensor pairs
+	reportStatus    = 0x20 // status report, carries the battery level
+)
+
+// bluezBackend talks to the balance board directly over BlueZ D-Bus (for
+// discovery and pairing) and raw L2CAP sockets (for the HID control and
+// interrupt channels), without going through the kernel's hid-wiimote
+// driver or the evdev subsystem. This lets the module run wherever BlueZ
+// is available, including containers without access to /dev/input/event*.
+type bluezBackend struct {
+	conn      *dbus.Conn
+	addr      string
+	control   *os.File
+	interrupt *os.File
+	battery   int
+}
+
+// DetectBluez scans paired and discoverable devices for a Class-of-Device
+// matching the Wii Balance Board, pairs with it if needed, opens the HID
+// control and interrupt PSMs, and switches it to the 0x32 report mode.
+func DetectBluez() (Backend, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't connect to the system D-Bus")
+	}
+
+	addr, err := findBluezBoard(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pairBluezBoard(conn, addr); err != nil {
+		return nil, errors.Wrapf(err, "couldn't pair with %s", addr)
+	}
+
+	control, err := dialL2CAP(addr, l2capPSMControl)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't open the HID control PSM")
+	}
+	interrupt, err := dialL2CAP(addr, l2capPSMInterrupt)
+	if err != nil {
+		control.Close()
+		return nil, errors.Wrap(err, "couldn't open the HID interrupt PSM")
+	}
+
+	b := &bluezBackend{conn: conn, addr: addr, control: control, interrupt: interrupt}
+
+	// 0x52 (SET_REPORT, output) 0x12 (data reporting mode) 0x00 0x32:
+	// report type 0x32, sent only when the data changes (the continuous
+	// bit 0x04 is not set).
+	if _, err := control.Write([]byte{0x52, 0x12, 0x00, reportInputData}); err != nil {
+		b.Close()
+		return nil, errors.Wrap(err, "couldn't request the 0x32 report mode")
+	}
+
+	return b, nil
+}
+
+// findBluezBoard walks BlueZ's managed objects looking for a device whose
+// reported Class matches the Wii Balance Board.
+func findBluezBoard(conn *dbus.Conn) (string, error) {
+	obj := conn.Object(bluezService, dbus.ObjectPath("/"))
+	var managed map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := obj.Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).Store(&managed); err != nil {
+		return "", errors.Wrap(err, "couldn't list BlueZ managed objects")
+	}
+
+	for _, ifaces := range managed {
+		dev, ok := ifaces["org.bluez.Device1"]
+		if !ok {
+			continue
+		}
+		class, ok := dev["Class"].Value().(uint32)
+		if !ok || class != classRVLWBC01 {
+			continue
+		}
+		if addr, ok := dev["Address"].Value().(string); ok {
+			return addr, nil
+		}
+	}
+
+	return "", errors.New("didn't find a Wii Balance Board over BlueZ")
+}
+
+// pairBluezBoard pairs with addr if it isn't already paired. The balance
+// board accepts Secure Simple Pairing with no PIN and no user
+// confirmation, so a bare Pair() call is enough.
+func pairBluezBoard(conn *dbus.Conn, addr string) error {
+	path := dbus.ObjectPath("/org/bluez/hci0/dev_" + strings.ReplaceAll(addr, ":", "_"))
+	obj := conn.Object(bluezService, path)
+
+	var paired bool
+	err := obj.Call("org.freedesktop.DBus.Properties.Get", 0, "org.bluez.Device1", "Paired").Store(&paired)
+	if err == nil && paired {
+		return nil
+	}
+
+	return obj.Call("org.bluez.Device1.Pair", 0).Err
+}
+
+// dialL2CAP opens a connected L2CAP socket to addr on the given PSM.
+func dialL2CAP(addr string, psm uint16) (*os.File, error) {
+	fd, err := unix.Socket(unix.AF_BLUETOOTH, unix.SOCK_SEQPACKET, unix.BTPROTO_L2CAP)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't open L2CAP socket")
+	}
+
+	bdaddr, err := parseBdaddr(addr)
+	if err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	if err := unix.Connect(fd, &unix.SockaddrL2{PSM: psm, Addr: bdaddr}); err != nil {
+		unix.Close(fd)
+		return nil, errors.Wrapf(err, "couldn't connect to PSM 0x%02x", psm)
+	}
+
+	return os.NewFile(uintptr(fd), fmt.Sprintf("l2cap-psm-0x%02x", psm)), nil
+}
+
+// parseBdaddr turns a colon-separated Bluetooth address such as
+// "00:1F:C5:4A:B2:01" into bytes in the order written, as
+// unix.SockaddrL2.Addr expects; SockaddrL2 itself reverses them into the
+// little-endian bdaddr_t of the kernel's L2CAP sockaddr.
+func parseBdaddr(addr string) ([6]byte, error) {
+	var bdaddr [6]byte
+	parts := strings.Split(addr, ":")
+	if len(parts) != 6 {
+		return bdaddr, errors.Errorf("invalid Bluetooth address %q", addr)
+	}
+	for i := 0; i < 6; i++ {
+		b, err := strconv.ParseUint(parts[i], 16, 8)
+		if err != nil {
+			return bdaddr, errors.Wrapf(err, "invalid Bluetooth address %q", addr)
+		}
+		bdaddr[i] = byte(b)
+	}
+	return bdaddr, nil
+}
+
+// Read implements Backend. It blocks until one HID input report is
+// available on the interrupt channel and returns the decoded Event.
+func (b *bluezBackend) Read() ([]Event, error) {
+	buf := make([]byte, 23)
+	n, err := b.interrupt.Read(buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read from the HID interrupt channel")
+	}
+	// buf[0] is the HID transaction type (0xA1 DATA Input), buf[1] the report ID.
+	if n < 2 {
+		return nil, nil
+	}
+
+	switch buf[1] {
+	case reportStatus:
+		if n >= 7 {
+			b.battery = int(buf[6]) * 100 / 0xD0
+		}
+		return nil, nil
+
+	case reportInputData:
+		data := buf[2:n]
+		if len(data) < 8 {
+			return nil, errors.New("short 0x32 report")
+		}
+		e := Event{
+			TopRight:    int32(binary.BigEndian.Uint16(data[0:2])),
+			BottomRight: int32(binary.BigEndian.Uint16(data[2:4])),
+			TopLeft:     int32(binary.BigEndian.Uint16(data[4:6])),
+			BottomLeft:  int32(binary.BigEndian.Uint16(data[6:8])),
+		}
+		e.Total = float64(e.TopLeft + e.TopRight + e.BottomLeft + e.BottomRight)
+		return []Event{e}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// Battery implements Backend. It returns the level from the last 0x20
+// status report seen on the interrupt channel.
+func (b *bluezBackend) Battery() (int, error) {
+	return b.battery, nil
+}
+
+// Serial returns the board's Bluetooth address, used to tag saved
+// Calibrations.
+func (b *bluezBackend) Serial() string {
+	return b.addr
+}
+
+// Close implements Backend.
+func (b *bluezBackend) Close() error {
+	var err error
+	if b.interrupt != nil {
+		if e := b.interrupt.Close(); e != nil {
+			err = e
+		}
+	}
+	if b.control != nil {
+		if e := b.control.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
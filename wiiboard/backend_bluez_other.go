@@ -0,0 +1,11 @@
+//go:build !linux
+
+package wiiboard
+
+import "github.com/pkg/errors"
+
+// DetectBluez is only implemented on Linux, where BlueZ's D-Bus API and
+// raw L2CAP sockets are available.
+func DetectBluez() (Backend, error) {
+	return nil, errors.New("BlueZ backend is only supported on Linux")
+}
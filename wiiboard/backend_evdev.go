@@ -0,0 +1,172 @@
+//go:build linux
+
+package wiiboard
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	evdev "github.com/gvalkov/golang-evdev"
+	"github.com/pkg/errors"
+)
+
+const (
+	deviceglob      = "/dev/input/event*"
+	nintendoVendor  = 0x057E
+	wiiBoardProduct = 0x0306
+)
+
+// evdevBackend reads balance board events through the kernel's
+// hid-wiimote driver via the evdev input subsystem. It requires
+// hid-wiimote to be loaded and /dev/input/event* to be accessible.
+type evdevBackend struct {
+	conn        *evdev.InputDevice
+	batteryPath string
+	curEvent    Event
+}
+
+// detectEvdev scans /dev/input for the first connected WiiBoard exposed by
+// the hid-wiimote kernel driver.
+func detectEvdev() (*evdevBackend, error) {
+	devices, err := evdev.ListInputDevices(deviceglob)
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't list input device on system")
+	}
+
+	for _, dev := range devices {
+		if dev.Vendor != nintendoVendor || dev.Product != wiiBoardProduct {
+			continue
+		}
+
+		batteryPath, err := findBatteryPath()
+		if err != nil {
+			return nil, err
+		}
+
+		return &evdevBackend{conn: dev, batteryPath: batteryPath}, nil
+	}
+
+	return nil, errors.New("Didn't find WiiBoard")
+}
+
+// findBatteryPath locates the power_supply capacity file for the WiiBoard
+// currently listed in /proc/bus/input/devices.
+func findBatteryPath() (string, error) {
+	f, err := os.Open("/proc/bus/input/devices")
+	if err != nil {
+		return "", errors.Wrapf(err, "couldn't find input device list file")
+	}
+	defer f.Close()
+
+	boardStenza := false
+	matchBoard := fmt.Sprintf("Vendor=0%x Product=0%x", nintendoVendor, wiiBoardProduct)
+	re := regexp.MustCompile("S: Sysfs=(.*)")
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		t := scanner.Text()
+		if t == "" && boardStenza {
+			return "", errors.New("didn't find expected sys location in input device list file")
+		}
+		if strings.Contains(t, matchBoard) {
+			boardStenza = true
+		}
+		if !boardStenza {
+			continue
+		}
+		res := re.FindStringSubmatch(t)
+		if len(res) < 2 {
+			continue
+		}
+		m, err := filepath.Glob("/sys" + res[1] + "/device/power_supply/*/capacity")
+		if err != nil || len(m) != 1 {
+			return "", errors.New("didn't find expected battery capacity location")
+		}
+		return m[0], nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", errors.Wrapf(err, "error reading input device list file")
+	}
+
+	return "", errors.New("didn't find expected sys location in input device list file")
+}
+
+// Read implements Backend. It blocks on the underlying evdev device and
+// assembles raw EV_ABS/EV_SYN frames into completed sensor Events.
+func (b *evdevBackend) Read() ([]Event, error) {
+	raw, err := b.conn.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read from evdev device")
+	}
+	if len(raw) < 5 {
+		// skip incomplete batches
+		return nil, nil
+	}
+
+	var out []Event
+	for _, e := range raw {
+		switch e.Type {
+		case evdev.EV_SYN:
+			out = append(out, b.curEvent)
+			b.curEvent = Event{}
+
+		// pressure point
+		case evdev.EV_ABS:
+			switch e.Code {
+			case evdev.ABS_HAT0Y:
+				b.curEvent.BottomRight = e.Value
+			case evdev.ABS_HAT1Y:
+				b.curEvent.BottomLeft = e.Value
+			case evdev.ABS_HAT0X:
+				b.curEvent.TopRight = e.Value
+			case evdev.ABS_HAT1X:
+				b.curEvent.TopLeft = e.Value
+			default:
+				continue
+			}
+			b.curEvent.Total = float64(b.curEvent.TopLeft + b.curEvent.TopRight + b.curEvent.BottomLeft + b.curEvent.BottomRight)
+
+		// main button
+		case evdev.EV_KEY:
+			if e.Code == 304 {
+				b.curEvent.Button = true
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Battery implements Backend.
+func (b *evdevBackend) Battery() (int, error) {
+	raw, err := ioutil.ReadFile(b.batteryPath)
+	if err != nil {
+		return 0, errors.Wrap(err, "couldn't read from board battery file")
+	}
+	battery, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0, errors.Wrap(err, "didn't find an integer in battery capacity file")
+	}
+	return battery, nil
+}
+
+// Serial returns the device's physical path, which is stable across
+// reconnects of the same board and used to tag saved Calibrations.
+func (b *evdevBackend) Serial() string {
+	return b.conn.Phys
+}
+
+// Close implements Backend.
+func (b *evdevBackend) Close() error {
+	if b.conn == nil || b.conn.File == nil {
+		return nil
+	}
+	return b.conn.File.Close()
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package wiiboard
+
+import "github.com/pkg/errors"
+
+// detectEvdev is only implemented on Linux, where the hid-wiimote kernel
+// driver exposes the board through /dev/input/event*.
+func detectEvdev() (Backend, error) {
+	return nil, errors.New("evdev backend is only supported on Linux")
+}
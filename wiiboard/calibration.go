@@ -0,0 +1,201 @@
+package wiiboard
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sensor indices into Calibration.Offsets/Scales, matching Event's field order.
+const (
+	sensorTopLeft = iota
+	sensorTopRight
+	sensorBottomRight
+	sensorBottomLeft
+)
+
+// Calibration holds the per-sensor zero offset and scale factor fitted
+// from a two-point calibration (Tare + CalibrateWithReference), persisted
+// so a board doesn't need to be recalibrated on every Detect.
+type Calibration struct {
+	Offsets     [4]int32
+	Scales      [4]float64
+	CreatedAt   time.Time
+	BoardSerial string
+}
+
+// apply converts summed-over-n raw sensor totals into a calibrated weight
+// in kilograms, using this Calibration's per-sensor offsets and scales.
+func (c *Calibration) apply(topLeft, topRight, bottomRight, bottomLeft, n int32) float64 {
+	raw := [4]float64{
+		float64(topLeft) / float64(n),
+		float64(topRight) / float64(n),
+		float64(bottomRight) / float64(n),
+		float64(bottomLeft) / float64(n),
+	}
+
+	var kg float64
+	for i, r := range raw {
+		kg += (r - float64(c.Offsets[i])) * c.Scales[i]
+	}
+	return kg
+}
+
+func calibrationPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", errors.Wrap(err, "couldn't determine home directory")
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "wii-scale", "calibration.json"), nil
+}
+
+func loadCalibration() (*Calibration, error) {
+	path, err := calibrationPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read calibration file")
+	}
+	var c Calibration
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, errors.Wrap(err, "couldn't parse calibration file")
+	}
+	return &c, nil
+}
+
+func (c *Calibration) save() error {
+	path, err := calibrationPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "couldn't create calibration directory")
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "couldn't encode calibration")
+	}
+	return errors.Wrap(os.WriteFile(path, data, 0644), "couldn't write calibration file")
+}
+
+// Tare records per-sensor zero offsets with the board empty, waiting for a
+// stable stance the same way sendMeanTotal does.
+func (w *wiiBoard) Tare(ctx context.Context) error {
+	raw, err := w.averageRaw(ctx)
+	if err != nil {
+		return errors.Wrap(err, "couldn't tare")
+	}
+
+	w.mux.Lock()
+	if w.calibration == nil {
+		w.calibration = &Calibration{}
+	}
+	w.calibration.Offsets = [4]int32{
+		int32(raw[sensorTopLeft]), int32(raw[sensorTopRight]),
+		int32(raw[sensorBottomRight]), int32(raw[sensorBottomLeft]),
+	}
+	w.calibration.CreatedAt = time.Now()
+	w.calibration.BoardSerial = w.boardSerial
+	cal := *w.calibration
+	w.mux.Unlock()
+
+	return cal.save()
+}
+
+// CalibrateWithReference records a second calibration point with a known
+// mass on the board and fits a per-sensor scale factor k_i = (knownKg/4) /
+// (raw_i - offset_i). Tare must be called first.
+func (w *wiiBoard) CalibrateWithReference(ctx context.Context, knownKg float64) error {
+	w.mux.RLock()
+	cal := w.calibration
+	w.mux.RUnlock()
+	if cal == nil {
+		return errors.New("board hasn't been tared yet")
+	}
+
+	raw, err := w.averageRaw(ctx)
+	if err != nil {
+		return errors.Wrap(err, "couldn't calibrate")
+	}
+
+	var scales [4]float64
+	for i, r := range raw {
+		delta := r - float64(cal.Offsets[i])
+		if delta == 0 {
+			return errors.Errorf("sensor %d didn't move, can't fit a scale factor", i)
+		}
+		scales[i] = (knownKg / 4) / delta
+	}
+
+	w.mux.Lock()
+	w.calibration.Scales = scales
+	w.calibration.CreatedAt = time.Now()
+	updated := *w.calibration
+	w.mux.Unlock()
+
+	return updated.save()
+}
+
+// averageRaw waits for a stable stance, mirroring sendMeanTotal's
+// stabilization loop, and returns the average raw reading of each of the
+// four sensors in Event field order (TopLeft, TopRight, BottomRight,
+// BottomLeft).
+func (w *wiiBoard) averageRaw(ctx context.Context) ([4]float64, error) {
+	id, ch := w.subscribeEvents()
+	defer w.unsubscribeEvents(id)
+
+	var sums [4]float64
+	var n int32
+	measureTime := time.Now().Add(3 * time.Second)
+	lastTotal := int32(0)
+
+	for {
+		if time.Now().After(measureTime) && n > 100 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return [4]float64{}, ctx.Err()
+		case e := <-ch:
+			// skips if one sensor sends 0, as we want an equilibrium state, we skip this invalid measure
+			if e.TopLeft == 0 || e.TopRight == 0 || e.BottomLeft == 0 || e.BottomRight == 0 {
+				continue
+			}
+
+			newTotal := e.TopLeft + e.TopRight + e.BottomRight + e.BottomLeft
+			// reset if changed by more than 20%: not stable yet!
+			if math.Abs(float64(lastTotal-newTotal))/float64(newTotal) > 0.2 {
+				sums = [4]float64{}
+				n = 0
+				measureTime = time.Now().Add(3 * time.Second)
+				lastTotal = newTotal
+				continue
+			}
+
+			lastTotal = newTotal
+			sums[sensorTopLeft] += float64(e.TopLeft)
+			sums[sensorTopRight] += float64(e.TopRight)
+			sums[sensorBottomRight] += float64(e.BottomRight)
+			sums[sensorBottomLeft] += float64(e.BottomLeft)
+			n++
+		case <-time.After(5 * time.Second):
+			return [4]float64{}, errors.New("timed out waiting for a stable stance")
+		}
+	}
+
+	for i := range sums {
+		sums[i] /= float64(n)
+	}
+	return sums, nil
+}
@@ -0,0 +1,51 @@
+package wiiboard
+
+import "testing"
+
+func TestCalibrationApply(t *testing.T) {
+	tests := []struct {
+		name                                          string
+		offsets                                       [4]int32
+		scales                                        [4]float64
+		topLeft, topRight, bottomRight, bottomLeft, n int32
+		want                                          float64
+	}{
+		{
+			name:    "no offset, unit scale, single sample",
+			scales:  [4]float64{1, 1, 1, 1},
+			topLeft: 10, topRight: 10, bottomRight: 10, bottomLeft: 10, n: 1,
+			want: 40,
+		},
+		{
+			name:    "offsets subtracted before scaling",
+			offsets: [4]int32{5, 5, 5, 5},
+			scales:  [4]float64{1, 1, 1, 1},
+			topLeft: 10, topRight: 10, bottomRight: 10, bottomLeft: 10, n: 1,
+			want: 20,
+		},
+		{
+			name:    "n averages the summed raw readings first",
+			offsets: [4]int32{0, 0, 0, 0},
+			scales:  [4]float64{2, 2, 2, 2},
+			topLeft: 20, topRight: 20, bottomRight: 20, bottomLeft: 20, n: 2,
+			want: 80,
+		},
+		{
+			name:    "per-sensor scale factors differ",
+			offsets: [4]int32{0, 0, 0, 0},
+			scales:  [4]float64{1, 2, 3, 4},
+			topLeft: 10, topRight: 10, bottomRight: 10, bottomLeft: 10, n: 1,
+			want: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Calibration{Offsets: tt.offsets, Scales: tt.scales}
+			got := c.apply(tt.topLeft, tt.topRight, tt.bottomRight, tt.bottomLeft, tt.n)
+			if got != tt.want {
+				t.Errorf("apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
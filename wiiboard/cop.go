@@ -0,0 +1,124 @@
+package wiiboard
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Active sensing area of the balance board, per the Wii Balance Board
+// spec: a 433 x 238 mm rectangle with a sensor at each corner.
+const (
+	boardWidthMM  = 433.0
+	boardHeightMM = 238.0
+
+	defaultSwayWindow = time.Second
+)
+
+// COPSample is a single center-of-pressure reading, in millimetres from
+// the board's center, alongside a rolling measure of postural sway.
+type COPSample struct {
+	X, Y      float64
+	Sway      float64
+	Timestamp time.Time
+}
+
+type copHistoryEntry struct {
+	x, y float64
+	t    time.Time
+}
+
+// SetSwayWindow sets the window used to compute the rolling RMS sway
+// radius sent with every COPSample. Defaults to 1s; must be called before
+// StartCOP.
+func (w *wiiBoard) SetSwayWindow(window time.Duration) {
+	w.swayWindow = window
+}
+
+// StartCOP begins decimating the raw sensor stream into center-of-pressure
+// samples at hz, sending them on COP until ctx is canceled. Each sample
+// carries the current CoP position plus an RMS sway radius computed over
+// the last SetSwayWindow of samples.
+func (w *wiiBoard) StartCOP(ctx context.Context, hz int) {
+	if hz <= 0 {
+		hz = 50
+	}
+	window := w.swayWindow
+	if window <= 0 {
+		window = defaultSwayWindow
+	}
+
+	go func() {
+		id, ch := w.subscribeEvents()
+		defer w.unsubscribeEvents(id)
+
+		ticker := time.NewTicker(time.Second / time.Duration(hz))
+		defer ticker.Stop()
+
+		var last Event
+		var history []copHistoryEntry
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-ch:
+				last = e
+			case now := <-ticker.C:
+				if last.Total == 0 {
+					continue
+				}
+				x, y := centerOfPressure(last)
+				history = append(history, copHistoryEntry{x: x, y: y, t: now})
+				history = trimHistory(history, now, window)
+
+				sample := COPSample{X: x, Y: y, Sway: rmsSway(history), Timestamp: now}
+				select {
+				case w.COP <- sample:
+				default:
+				}
+			}
+		}
+	}()
+}
+
+// centerOfPressure computes the X/Y offset from the board's center, in
+// millimetres, from the four corner sensor readings of e.
+func centerOfPressure(e Event) (x, y float64) {
+	x = float64((e.TopRight+e.BottomRight)-(e.TopLeft+e.BottomLeft)) / e.Total * (boardWidthMM / 2)
+	y = float64((e.TopLeft+e.TopRight)-(e.BottomLeft+e.BottomRight)) / e.Total * (boardHeightMM / 2)
+	return x, y
+}
+
+// trimHistory drops entries older than window relative to now.
+func trimHistory(history []copHistoryEntry, now time.Time, window time.Duration) []copHistoryEntry {
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(history) && history[i].t.Before(cutoff) {
+		i++
+	}
+	return history[i:]
+}
+
+// rmsSway returns the RMS distance of history's samples from their mean
+// position: a stability radius in millimetres, zero for an empty window.
+func rmsSway(history []copHistoryEntry) float64 {
+	if len(history) == 0 {
+		return 0
+	}
+
+	var meanX, meanY float64
+	for _, s := range history {
+		meanX += s.x
+		meanY += s.y
+	}
+	meanX /= float64(len(history))
+	meanY /= float64(len(history))
+
+	var sumSq float64
+	for _, s := range history {
+		dx := s.x - meanX
+		dy := s.y - meanY
+		sumSq += dx*dx + dy*dy
+	}
+	return math.Sqrt(sumSq / float64(len(history)))
+}
@@ -0,0 +1,73 @@
+package wiiboard
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCenterOfPressure(t *testing.T) {
+	tests := []struct {
+		name  string
+		e     Event
+		wantX float64
+		wantY float64
+	}{
+		{
+			name:  "even load is centered",
+			e:     Event{TopLeft: 10, TopRight: 10, BottomRight: 10, BottomLeft: 10, Total: 40},
+			wantX: 0, wantY: 0,
+		},
+		{
+			name:  "all weight on the right edge",
+			e:     Event{TopLeft: 0, TopRight: 10, BottomRight: 10, BottomLeft: 0, Total: 20},
+			wantX: boardWidthMM / 2, wantY: 0,
+		},
+		{
+			name:  "all weight on the top edge",
+			e:     Event{TopLeft: 10, TopRight: 10, BottomRight: 0, BottomLeft: 0, Total: 20},
+			wantX: 0, wantY: boardHeightMM / 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := centerOfPressure(tt.e)
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("centerOfPressure() = (%v, %v), want (%v, %v)", x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestTrimHistory(t *testing.T) {
+	now := time.Unix(100, 0)
+	history := []copHistoryEntry{
+		{t: now.Add(-3 * time.Second)},
+		{t: now.Add(-2 * time.Second)},
+		{t: now.Add(-500 * time.Millisecond)},
+	}
+
+	got := trimHistory(history, now, time.Second)
+	if len(got) != 1 {
+		t.Fatalf("trimHistory() kept %d entries, want 1", len(got))
+	}
+	if !got[0].t.Equal(now.Add(-500 * time.Millisecond)) {
+		t.Errorf("trimHistory() kept the wrong entry: %v", got[0].t)
+	}
+}
+
+func TestRMSSway(t *testing.T) {
+	if got := rmsSway(nil); got != 0 {
+		t.Errorf("rmsSway(nil) = %v, want 0", got)
+	}
+
+	history := []copHistoryEntry{
+		{x: 3, y: 4},
+		{x: -3, y: -4},
+	}
+	// both points sit 5mm from their mean (0, 0), so the RMS radius is 5.
+	if got, want := rmsSway(history), 5.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("rmsSway() = %v, want %v", got, want)
+	}
+}
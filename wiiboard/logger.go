@@ -0,0 +1,98 @@
+package wiiboard
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// Permission gates whether a log entry should be emitted. A host
+// application running more than one board satisfies this with a board's
+// Env, so it can suppress noise from a secondary board while still
+// logging from the primary one.
+type Permission interface {
+	// Label identifies the source of the entry, e.g. a board's identity.
+	Label() string
+	// Allowed reports whether entries tagged with this Permission should
+	// be emitted.
+	Allowed() bool
+}
+
+// Logger receives board log entries tagged with a Permission. Implementations
+// decide whether and where to write based on perm.Allowed() and perm.Label().
+type Logger interface {
+	Log(perm Permission, msg string)
+	Logf(perm Permission, format string, args ...any)
+}
+
+// Env identifies a board instance for logging purposes. It is itself a
+// Permission: always labeled with the board's identity, and allowed
+// unless the board has been muted.
+type Env struct {
+	BoardSerial string
+	muted       bool
+}
+
+// Label implements Permission.
+func (e Env) Label() string {
+	if e.BoardSerial == "" {
+		return "wiiboard"
+	}
+	return "wiiboard[" + e.BoardSerial + "]"
+}
+
+// Allowed implements Permission.
+func (e Env) Allowed() bool {
+	return !e.muted
+}
+
+// Mute suppresses future log entries tagged with this Env, for a host
+// application running a noisy secondary board.
+func (e *Env) Mute() {
+	e.muted = true
+}
+
+// Unmute re-enables log entries tagged with this Env.
+func (e *Env) Unmute() {
+	e.muted = false
+}
+
+type alwaysAllow struct{}
+
+func (alwaysAllow) Label() string { return "wiiboard" }
+func (alwaysAllow) Allowed() bool { return true }
+
+// AlwaysAllow is a Permission that is always Allowed, for callers who want
+// unconditional log entries regardless of any board's mute state.
+var AlwaysAllow Permission = alwaysAllow{}
+
+type discardLogger struct{}
+
+func (discardLogger) Log(Permission, string)          {}
+func (discardLogger) Logf(Permission, string, ...any) {}
+
+// LogrusLogger adapts a *logrus.Logger to the Logger interface, writing
+// entries whose Permission is Allowed with its Label as a field. This
+// preserves the package's previous logrus-based logging as an opt-in.
+type LogrusLogger struct {
+	*log.Logger
+}
+
+// NewLogrusLogger wraps l as a Logger.
+func NewLogrusLogger(l *log.Logger) *LogrusLogger {
+	return &LogrusLogger{Logger: l}
+}
+
+// Log implements Logger.
+func (l *LogrusLogger) Log(perm Permission, msg string) {
+	if !perm.Allowed() {
+		return
+	}
+	l.Logger.WithField("board", perm.Label()).Info(msg)
+}
+
+// Logf implements Logger.
+func (l *LogrusLogger) Logf(perm Permission, format string, args ...any) {
+	if !perm.Allowed() {
+		return
+	}
+	l.Logger.WithField("board", perm.Label()).Infof(format, args...)
+}
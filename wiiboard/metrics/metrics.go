@@ -0,0 +1,105 @@
+// Package metrics exposes Prometheus metrics for a running wii-scale board.
+// It attaches to a board as a wiiboard.Hook, so it observes events without
+// the core wiiboard package knowing anything about Prometheus.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/tectiv3/wii-scale/wiiboard"
+)
+
+// Collector implements wiiboard.Hook and serves the collected state on an
+// HTTP /metrics endpoint in the Prometheus exposition format.
+type Collector struct {
+	registry *prometheus.Registry
+
+	lastWeight   prometheus.Gauge
+	battery      prometheus.Gauge
+	sensorRaw    *prometheus.GaugeVec
+	measurements prometheus.Counter
+	calibrations *prometheus.CounterVec
+	settleTime   prometheus.Histogram
+}
+
+// New creates a Collector with its own registry, ready to be attached to a
+// board with wiiBoard.AddHook and served with Handler.
+func New() *Collector {
+	reg := prometheus.NewRegistry()
+
+	c := &Collector{
+		registry: reg,
+		lastWeight: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "wiiscale_last_weight_kg",
+			Help: "Last calibrated weight reported by the board, in kilograms.",
+		}),
+		battery: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "wiiscale_battery_percent",
+			Help: "Last known battery level of the board, in percent.",
+		}),
+		sensorRaw: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "wiiscale_sensor_raw",
+			Help: "Raw sensor reading of the last event, per corner.",
+		}, []string{"position"}),
+		measurements: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "wiiscale_measurements_total",
+			Help: "Number of completed weight measurements.",
+		}),
+		calibrations: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "wiiscale_calibrations_total",
+			Help: "Number of calibration attempts, by result.",
+		}, []string{"result"}),
+		settleTime: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "wiiscale_settle_time_seconds",
+			Help:    "Time from the start of a stable stance to a completed measurement.",
+			Buckets: prometheus.DefBuckets,
+		}),
+	}
+
+	// initialize the result label set so they show up as zero until hit
+	for _, result := range []string{"ok", "timeout", "reset"} {
+		c.calibrations.WithLabelValues(result)
+	}
+
+	return c
+}
+
+// OnSensors implements wiiboard.Hook.
+func (c *Collector) OnSensors(e wiiboard.Event) {
+	c.sensorRaw.WithLabelValues("topleft").Set(float64(e.TopLeft))
+	c.sensorRaw.WithLabelValues("topright").Set(float64(e.TopRight))
+	c.sensorRaw.WithLabelValues("bottomleft").Set(float64(e.BottomLeft))
+	c.sensorRaw.WithLabelValues("bottomright").Set(float64(e.BottomRight))
+}
+
+// OnWeight implements wiiboard.Hook.
+func (c *Collector) OnWeight(kg float64) {
+	c.lastWeight.Set(kg)
+	c.measurements.Inc()
+}
+
+// OnCalibration implements wiiboard.Hook.
+func (c *Collector) OnCalibration(result string, settle time.Duration) {
+	c.calibrations.WithLabelValues(result).Inc()
+	if result == "ok" {
+		c.settleTime.Observe(settle.Seconds())
+	}
+}
+
+// SetBattery records the board's last known battery level. Callers poll
+// wiiBoard.Battery() themselves and feed the result here, since battery
+// level isn't part of the Listen/sendMeanTotal event flow.
+func (c *Collector) SetBattery(percent int) {
+	c.battery.Set(float64(percent))
+}
+
+// Handler returns an http.Handler serving this collector's metrics in the
+// Prometheus exposition format.
+func (c *Collector) Handler() http.Handler {
+	return promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{})
+}
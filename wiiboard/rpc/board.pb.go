@@ -0,0 +1,195 @@
+// Hand-written messages for board.proto, using the legacy protoc-gen-go
+// v1 API (Reset/String/ProtoMessage + struct tags) so they work with
+// grpc's codec without pulling in protoc or the protoc-gen-go/-grpc
+// plugins. Keep this file in sync with board.proto by hand; it is not
+// machine generated.
+// source: board.proto
+
+package rpc
+
+import "fmt"
+
+// Event mirrors wiiboard.Event plus a board identifier and a timestamp,
+// so a client subscribed to more than one board can tell their streams
+// apart and order samples across a restart. The timestamp is the server's
+// wall-clock time in Unix nanoseconds; it is not monotonic and follows
+// any adjustment of the server's clock.
+type Event struct {
+	TopLeft           int32   `protobuf:"varint,1,opt,name=top_left,json=topLeft,proto3" json:"top_left,omitempty"`
+	TopRight          int32   `protobuf:"varint,2,opt,name=top_right,json=topRight,proto3" json:"top_right,omitempty"`
+	BottomRight       int32   `protobuf:"varint,3,opt,name=bottom_right,json=bottomRight,proto3" json:"bottom_right,omitempty"`
+	BottomLeft        int32   `protobuf:"varint,4,opt,name=bottom_left,json=bottomLeft,proto3" json:"bottom_left,omitempty"`
+	Total             float64 `protobuf:"fixed64,5,opt,name=total,proto3" json:"total,omitempty"`
+	Button            bool    `protobuf:"varint,6,opt,name=button,proto3" json:"button,omitempty"`
+	BoardId           string  `protobuf:"bytes,7,opt,name=board_id,json=boardId,proto3" json:"board_id,omitempty"`
+	TimestampUnixNano int64   `protobuf:"varint,8,opt,name=timestamp_unix_nano,json=timestampUnixNano,proto3" json:"timestamp_unix_nano,omitempty"`
+}
+
+func (x *Event) Reset()         { *x = Event{} }
+func (x *Event) String() string { return fmt.Sprintf("%+v", *x) }
+func (*Event) ProtoMessage()    {}
+
+func (x *Event) GetTopLeft() int32 {
+	if x != nil {
+		return x.TopLeft
+	}
+	return 0
+}
+
+func (x *Event) GetTopRight() int32 {
+	if x != nil {
+		return x.TopRight
+	}
+	return 0
+}
+
+func (x *Event) GetBottomRight() int32 {
+	if x != nil {
+		return x.BottomRight
+	}
+	return 0
+}
+
+func (x *Event) GetBottomLeft() int32 {
+	if x != nil {
+		return x.BottomLeft
+	}
+	return 0
+}
+
+func (x *Event) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Event) GetButton() bool {
+	if x != nil {
+		return x.Button
+	}
+	return false
+}
+
+func (x *Event) GetBoardId() string {
+	if x != nil {
+		return x.BoardId
+	}
+	return ""
+}
+
+func (x *Event) GetTimestampUnixNano() int64 {
+	if x != nil {
+		return x.TimestampUnixNano
+	}
+	return 0
+}
+
+// WeightSample mirrors a single calibrated reading sent on wiiBoard.Weights.
+type WeightSample struct {
+	Kg                float64 `protobuf:"fixed64,1,opt,name=kg,proto3" json:"kg,omitempty"`
+	BoardId           string  `protobuf:"bytes,2,opt,name=board_id,json=boardId,proto3" json:"board_id,omitempty"`
+	TimestampUnixNano int64   `protobuf:"varint,3,opt,name=timestamp_unix_nano,json=timestampUnixNano,proto3" json:"timestamp_unix_nano,omitempty"`
+}
+
+func (x *WeightSample) Reset()         { *x = WeightSample{} }
+func (x *WeightSample) String() string { return fmt.Sprintf("%+v", *x) }
+func (*WeightSample) ProtoMessage()    {}
+
+func (x *WeightSample) GetKg() float64 {
+	if x != nil {
+		return x.Kg
+	}
+	return 0
+}
+
+func (x *WeightSample) GetBoardId() string {
+	if x != nil {
+		return x.BoardId
+	}
+	return ""
+}
+
+func (x *WeightSample) GetTimestampUnixNano() int64 {
+	if x != nil {
+		return x.TimestampUnixNano
+	}
+	return 0
+}
+
+type Empty struct{}
+
+func (x *Empty) Reset()         { *x = Empty{} }
+func (x *Empty) String() string { return "Empty{}" }
+func (*Empty) ProtoMessage()    {}
+
+// StreamWeightsRequest's BoardId is reserved for a future gateway
+// multiplexing several boards over one BoardService; a single-board
+// server ignores it.
+type StreamWeightsRequest struct {
+	BoardId string `protobuf:"bytes,1,opt,name=board_id,json=boardId,proto3" json:"board_id,omitempty"`
+}
+
+func (x *StreamWeightsRequest) Reset()         { *x = StreamWeightsRequest{} }
+func (x *StreamWeightsRequest) String() string { return fmt.Sprintf("%+v", *x) }
+func (*StreamWeightsRequest) ProtoMessage()    {}
+
+func (x *StreamWeightsRequest) GetBoardId() string {
+	if x != nil {
+		return x.BoardId
+	}
+	return ""
+}
+
+type BatteryReply struct {
+	Percent int32 `protobuf:"varint,1,opt,name=percent,proto3" json:"percent,omitempty"`
+}
+
+func (x *BatteryReply) Reset()         { *x = BatteryReply{} }
+func (x *BatteryReply) String() string { return fmt.Sprintf("%+v", *x) }
+func (*BatteryReply) ProtoMessage()    {}
+
+func (x *BatteryReply) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+type CalibrateRequest struct {
+	KnownKg float64 `protobuf:"fixed64,1,opt,name=known_kg,json=knownKg,proto3" json:"known_kg,omitempty"`
+}
+
+func (x *CalibrateRequest) Reset()         { *x = CalibrateRequest{} }
+func (x *CalibrateRequest) String() string { return fmt.Sprintf("%+v", *x) }
+func (*CalibrateRequest) ProtoMessage()    {}
+
+func (x *CalibrateRequest) GetKnownKg() float64 {
+	if x != nil {
+		return x.KnownKg
+	}
+	return 0
+}
+
+type CalibrateReply struct {
+	Ok    bool   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *CalibrateReply) Reset()         { *x = CalibrateReply{} }
+func (x *CalibrateReply) String() string { return fmt.Sprintf("%+v", *x) }
+func (*CalibrateReply) ProtoMessage()    {}
+
+func (x *CalibrateReply) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *CalibrateReply) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+// Hand-written gRPC client/server stubs for board.proto's BoardService,
+// written in the shape protoc-gen-go-grpc would produce so it drops in
+// against google.golang.org/grpc without requiring protoc to build.
+// Keep this file in sync with board.proto by hand; it is not machine
+// generated.
+// source: board.proto
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	BoardService_StreamEvents_FullMethodName  = "/wiiboard.rpc.BoardService/StreamEvents"
+	BoardService_StreamWeights_FullMethodName = "/wiiboard.rpc.BoardService/StreamWeights"
+	BoardService_Battery_FullMethodName       = "/wiiboard.rpc.BoardService/Battery"
+	BoardService_Calibrate_FullMethodName     = "/wiiboard.rpc.BoardService/Calibrate"
+)
+
+// BoardServiceClient is the client API for BoardService.
+type BoardServiceClient interface {
+	StreamEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (BoardService_StreamEventsClient, error)
+	StreamWeights(ctx context.Context, in *StreamWeightsRequest, opts ...grpc.CallOption) (BoardService_StreamWeightsClient, error)
+	Battery(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BatteryReply, error)
+	Calibrate(ctx context.Context, in *CalibrateRequest, opts ...grpc.CallOption) (*CalibrateReply, error)
+}
+
+type boardServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewBoardServiceClient returns a client for BoardService over cc.
+func NewBoardServiceClient(cc grpc.ClientConnInterface) BoardServiceClient {
+	return &boardServiceClient{cc}
+}
+
+func (c *boardServiceClient) StreamEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (BoardService_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BoardService_ServiceDesc.Streams[0], BoardService_StreamEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &boardServiceStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BoardService_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type boardServiceStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *boardServiceStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *boardServiceClient) StreamWeights(ctx context.Context, in *StreamWeightsRequest, opts ...grpc.CallOption) (BoardService_StreamWeightsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BoardService_ServiceDesc.Streams[1], BoardService_StreamWeights_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &boardServiceStreamWeightsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BoardService_StreamWeightsClient interface {
+	Recv() (*WeightSample, error)
+	grpc.ClientStream
+}
+
+type boardServiceStreamWeightsClient struct {
+	grpc.ClientStream
+}
+
+func (x *boardServiceStreamWeightsClient) Recv() (*WeightSample, error) {
+	m := new(WeightSample)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *boardServiceClient) Battery(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BatteryReply, error) {
+	out := new(BatteryReply)
+	if err := c.cc.Invoke(ctx, BoardService_Battery_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) Calibrate(ctx context.Context, in *CalibrateRequest, opts ...grpc.CallOption) (*CalibrateReply, error) {
+	out := new(CalibrateReply)
+	if err := c.cc.Invoke(ctx, BoardService_Calibrate_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BoardServiceServer is the server API for BoardService.
+type BoardServiceServer interface {
+	StreamEvents(*Empty, BoardService_StreamEventsServer) error
+	StreamWeights(*StreamWeightsRequest, BoardService_StreamWeightsServer) error
+	Battery(context.Context, *Empty) (*BatteryReply, error)
+	Calibrate(context.Context, *CalibrateRequest) (*CalibrateReply, error)
+}
+
+// UnimplementedBoardServiceServer may be embedded to have forward
+// compatible implementations.
+type UnimplementedBoardServiceServer struct{}
+
+func (UnimplementedBoardServiceServer) StreamEvents(*Empty, BoardService_StreamEventsServer) error {
+	return status.Error(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedBoardServiceServer) StreamWeights(*StreamWeightsRequest, BoardService_StreamWeightsServer) error {
+	return status.Error(codes.Unimplemented, "method StreamWeights not implemented")
+}
+func (UnimplementedBoardServiceServer) Battery(context.Context, *Empty) (*BatteryReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method Battery not implemented")
+}
+func (UnimplementedBoardServiceServer) Calibrate(context.Context, *CalibrateRequest) (*CalibrateReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method Calibrate not implemented")
+}
+
+// RegisterBoardServiceServer registers srv with s.
+func RegisterBoardServiceServer(s grpc.ServiceRegistrar, srv BoardServiceServer) {
+	s.RegisterService(&BoardService_ServiceDesc, srv)
+}
+
+func _BoardService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BoardServiceServer).StreamEvents(m, &boardServiceStreamEventsServer{stream})
+}
+
+type BoardService_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type boardServiceStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *boardServiceStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BoardService_StreamWeights_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamWeightsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BoardServiceServer).StreamWeights(m, &boardServiceStreamWeightsServer{stream})
+}
+
+type BoardService_StreamWeightsServer interface {
+	Send(*WeightSample) error
+	grpc.ServerStream
+}
+
+type boardServiceStreamWeightsServer struct {
+	grpc.ServerStream
+}
+
+func (x *boardServiceStreamWeightsServer) Send(m *WeightSample) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BoardService_Battery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).Battery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: BoardService_Battery_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).Battery(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_Calibrate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalibrateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).Calibrate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: BoardService_Calibrate_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).Calibrate(ctx, req.(*CalibrateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BoardService_ServiceDesc is the grpc.ServiceDesc for BoardService.
+var BoardService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wiiboard.rpc.BoardService",
+	HandlerType: (*BoardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Battery", Handler: _BoardService_Battery_Handler},
+		{MethodName: "Calibrate", Handler: _BoardService_Calibrate_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "StreamEvents", Handler: _BoardService_StreamEvents_Handler, ServerStreams: true},
+		{StreamName: "StreamWeights", Handler: _BoardService_StreamWeights_Handler, ServerStreams: true},
+	},
+	Metadata: "board.proto",
+}
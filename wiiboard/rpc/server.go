@@ -0,0 +1,159 @@
+// Package rpc implements BoardService, a gRPC server wrapping a running
+// wiiboard as a wiiboard.Hook: Server fans out each OnSensors/OnWeight
+// call to every subscribed StreamEvents/StreamWeights client, and
+// forwards Battery/Calibrate calls straight through to the board.
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tectiv3/wii-scale/wiiboard"
+)
+
+// boardAccessor is the subset of wiiboard's board type the RPC server
+// needs. The board type itself is unexported, so callers pass in a pointer
+// to a board, which satisfies this interface; see cmd/wii-scaled.
+type boardAccessor interface {
+	AddHook(wiiboard.Hook)
+	Battery() (int, error)
+	CalibrateWithReference(ctx context.Context, knownKg float64) error
+}
+
+// Server implements BoardServiceServer by wrapping a board as a
+// wiiboard.Hook and fanning out its events and weights to every
+// subscribed stream.
+type Server struct {
+	UnimplementedBoardServiceServer
+
+	board   boardAccessor
+	boardID string
+
+	mu         sync.Mutex
+	nextSub    int
+	eventSubs  map[int]chan *Event
+	weightSubs map[int]chan *WeightSample
+}
+
+// NewServer wraps board as a BoardServiceServer, tagging every streamed
+// Event and WeightSample with boardID.
+func NewServer(board boardAccessor, boardID string) *Server {
+	s := &Server{
+		board:      board,
+		boardID:    boardID,
+		eventSubs:  make(map[int]chan *Event),
+		weightSubs: make(map[int]chan *WeightSample),
+	}
+	board.AddHook(s)
+	return s
+}
+
+// OnSensors implements wiiboard.Hook.
+func (s *Server) OnSensors(e wiiboard.Event) {
+	out := &Event{
+		TopLeft:           e.TopLeft,
+		TopRight:          e.TopRight,
+		BottomRight:       e.BottomRight,
+		BottomLeft:        e.BottomLeft,
+		Total:             e.Total,
+		Button:            e.Button,
+		BoardId:           s.boardID,
+		TimestampUnixNano: time.Now().UnixNano(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.eventSubs {
+		select {
+		case ch <- out:
+		default:
+		}
+	}
+}
+
+// OnWeight implements wiiboard.Hook.
+func (s *Server) OnWeight(kg float64) {
+	out := &WeightSample{
+		Kg:                kg,
+		BoardId:           s.boardID,
+		TimestampUnixNano: time.Now().UnixNano(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.weightSubs {
+		select {
+		case ch <- out:
+		default:
+		}
+	}
+}
+
+// OnCalibration implements wiiboard.Hook. BoardService doesn't stream
+// calibration lifecycle events, so there's nothing to do here.
+func (s *Server) OnCalibration(result string, settle time.Duration) {}
+
+// StreamEvents implements BoardServiceServer.
+func (s *Server) StreamEvents(_ *Empty, stream BoardService_StreamEventsServer) error {
+	id, ch := subscribe(&s.mu, s.eventSubs, &s.nextSub)
+	defer unsubscribe(&s.mu, s.eventSubs, id)
+	return pump(stream.Context(), ch, stream.Send)
+}
+
+// StreamWeights implements BoardServiceServer.
+func (s *Server) StreamWeights(_ *StreamWeightsRequest, stream BoardService_StreamWeightsServer) error {
+	id, ch := subscribe(&s.mu, s.weightSubs, &s.nextSub)
+	defer unsubscribe(&s.mu, s.weightSubs, id)
+	return pump(stream.Context(), ch, stream.Send)
+}
+
+// subscribe registers a new buffered channel under a fresh id in subs.
+func subscribe[T any](mu *sync.Mutex, subs map[int]chan T, nextID *int) (id int, ch chan T) {
+	ch = make(chan T, 16)
+	mu.Lock()
+	id = *nextID
+	*nextID++
+	subs[id] = ch
+	mu.Unlock()
+	return id, ch
+}
+
+// unsubscribe removes the channel registered under id from subs.
+func unsubscribe[T any](mu *sync.Mutex, subs map[int]chan T, id int) {
+	mu.Lock()
+	delete(subs, id)
+	mu.Unlock()
+}
+
+// pump forwards values from ch to send until ctx is done or send errors.
+func pump[T any](ctx context.Context, ch <-chan T, send func(T) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case v := <-ch:
+			if err := send(v); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Battery implements BoardServiceServer.
+func (s *Server) Battery(context.Context, *Empty) (*BatteryReply, error) {
+	percent, err := s.board.Battery()
+	if err != nil {
+		return nil, err
+	}
+	return &BatteryReply{Percent: int32(percent)}, nil
+}
+
+// Calibrate implements BoardServiceServer. It assumes the board has
+// already been tared with the board empty; see wiiBoard.Tare.
+func (s *Server) Calibrate(ctx context.Context, req *CalibrateRequest) (*CalibrateReply, error) {
+	if err := s.board.CalibrateWithReference(ctx, req.KnownKg); err != nil {
+		return &CalibrateReply{Ok: false, Error: err.Error()}, nil
+	}
+	return &CalibrateReply{Ok: true}, nil
+}
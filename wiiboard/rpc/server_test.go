@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/tectiv3/wii-scale/wiiboard"
+)
+
+func TestServerOnSensorsFanOut(t *testing.T) {
+	s := &Server{
+		boardID:    "board-1",
+		eventSubs:  make(map[int]chan *Event),
+		weightSubs: make(map[int]chan *WeightSample),
+	}
+
+	id, ch := subscribe(&s.mu, s.eventSubs, &s.nextSub)
+	defer unsubscribe(&s.mu, s.eventSubs, id)
+
+	s.OnSensors(wiiboard.Event{TopLeft: 1, TopRight: 2, BottomRight: 3, BottomLeft: 4, Total: 10})
+
+	select {
+	case got := <-ch:
+		if got.BoardId != "board-1" || got.Total != 10 {
+			t.Errorf("OnSensors() sent %+v, want BoardId %q and Total 10", got, "board-1")
+		}
+	default:
+		t.Fatal("OnSensors() didn't deliver to the subscribed channel")
+	}
+}
+
+func TestServerOnSensorsNoSubscribers(t *testing.T) {
+	s := &Server{eventSubs: make(map[int]chan *Event), weightSubs: make(map[int]chan *WeightSample)}
+	// must not block or panic with no subscribers.
+	s.OnSensors(wiiboard.Event{})
+}
+
+func TestSubscribeUnsubscribe(t *testing.T) {
+	var mu sync.Mutex
+	subs := make(map[int]chan int)
+	var next int
+
+	id1, _ := subscribe(&mu, subs, &next)
+	id2, _ := subscribe(&mu, subs, &next)
+	if id1 == id2 {
+		t.Fatalf("subscribe() returned duplicate ids: %d, %d", id1, id2)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("len(subs) = %d, want 2", len(subs))
+	}
+
+	unsubscribe(&mu, subs, id1)
+	if _, ok := subs[id1]; ok {
+		t.Error("unsubscribe() left id1 in the map")
+	}
+	if _, ok := subs[id2]; !ok {
+		t.Error("unsubscribe() removed the wrong id")
+	}
+}
@@ -1,68 +1,33 @@
 package wiiboard
 
 import (
-	"bufio"
-	"fmt"
-	"io/ioutil"
-	stdlog "log"
 	"math"
 	"os"
-	"path/filepath"
-	"regexp"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
-	evdev "github.com/gvalkov/golang-evdev"
 	"github.com/pkg/errors"
 )
 
-const (
-	deviceglob      = "/dev/input/event*"
-	nintendoVendor  = 0x057E
-	wiiBoardProduct = 0x0306
-)
-
-var logrus *log.Logger
-
-func init() {
-	logrus = log.New()
-	// redirect Go standard log library calls to logrus writer
-	stdlog.SetFlags(0)
-	stdlog.SetOutput(logrus.Writer())
-	stdlog.SetFlags(stdlog.LstdFlags | stdlog.Lshortfile)
-	logrus.Out = os.Stdout
-
-	logrus.Level, _ = log.ParseLevel("debug")
-	log.SetLevel(logrus.Level)
-
-	f, err := os.OpenFile("/tmp/wii.log", os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
-	if err != nil {
-		logrus.Error(err)
-		return
-	}
-
-	log.SetOutput(f)
-	stdlog.SetOutput(f)
-	logrus.Out = f
-
-	logrus.Info("Started wii-scale")
-}
-
 // wiiBoard is the currently connected wiiboard connection
 type wiiBoard struct {
 	Weights chan float64
-
-	conn        *evdev.InputDevice
-	batteryPath string
-
-	calibrating bool
-	mux         *sync.RWMutex
-	lastWeight  float64
-	events      chan Event
+	COP     chan COPSample
+
+	backend Backend
+	logger  Logger
+	env     Env
+
+	calibrating  bool
+	calibration  *Calibration
+	boardSerial  string
+	mux          *sync.RWMutex
+	lastWeight   float64
+	lastRawTotal float64
+	eventSubs    map[int]chan Event
+	nextEventSub int
+	hooks        []Hook
+	swayWindow   time.Duration
 }
 
 // Event represents various pressure point generated by the wii balance board
@@ -75,152 +40,174 @@ type Event struct {
 	Button      bool
 }
 
-func New() wiiBoard {
+// Hook receives lifecycle notifications from a running board so that
+// external packages (metrics, logging, ...) can observe it without the
+// core package depending on them. Hooks must not block; a slow hook will
+// stall Listen/sendMeanTotal.
+type Hook interface {
+	// OnSensors is called with each raw sensor event Listen passes on.
+	// While no measurement is running, Listen drops events under its
+	// weight threshold and events that start a new measurement, and
+	// OnSensors is not called for those.
+	OnSensors(e Event)
+	// OnWeight is called with each new calibrated weight after it has been
+	// offered on Weights; the send is dropped when nobody is receiving.
+	OnWeight(kg float64)
+	// OnCalibration is called at each phase of sendMeanTotal's stabilization loop.
+	// result is one of "ok", "timeout" or "reset"; settle is only meaningful for "ok".
+	OnCalibration(result string, settle time.Duration)
+}
+
+// New creates a board ready for Detect. A nil logger discards all log
+// entries; pass NewLogrusLogger to keep the package's previous behavior.
+func New(logger Logger) wiiBoard {
+	if logger == nil {
+		logger = discardLogger{}
+	}
 	return wiiBoard{
-		mux:     &sync.RWMutex{},
-		events:  make(chan Event),
-		Weights: make(chan float64),
+		mux:        &sync.RWMutex{},
+		eventSubs:  make(map[int]chan Event),
+		Weights:    make(chan float64),
+		COP:        make(chan COPSample),
+		swayWindow: defaultSwayWindow,
+		logger:     logger,
 	}
 }
 
-// Detect enables picking first connected WiiBoard on the system
-func (w *wiiBoard) Detect() error {
-	devices, err := evdev.ListInputDevices(deviceglob)
-	if err != nil {
-		return errors.Wrapf(err, "couldn't list input device on system")
-	}
+// AddHook registers h to receive board lifecycle notifications. Safe to
+// call before Listen; not safe to call concurrently with Listen.
+func (w *wiiBoard) AddHook(h Hook) {
+	w.hooks = append(w.hooks, h)
+}
 
-	for _, dev := range devices {
-		if dev.Vendor != nintendoVendor || dev.Product != wiiBoardProduct {
-			continue
-		}
+// Env returns the board's logging Env, letting a caller holding more than
+// one board call Mute/Unmute on a specific one.
+func (w *wiiBoard) Env() *Env {
+	return &w.env
+}
 
-		// look for battery path
-		var batteryPath string
-		f, err := os.Open("/proc/bus/input/devices")
-		if err != nil {
-			return errors.Wrapf(err, "couldn't find input device list file")
-		}
-		defer f.Close()
+// subscribeEvents registers a new buffered channel that receives a copy of
+// every event Listen processes, until unsubscribeEvents is called with the
+// returned id. sendMeanTotal, averageRaw and StartCOP each subscribe their
+// own channel so they don't race each other for a single shared stream.
+func (w *wiiBoard) subscribeEvents() (id int, ch chan Event) {
+	ch = make(chan Event, 16)
+	w.mux.Lock()
+	id = w.nextEventSub
+	w.nextEventSub++
+	w.eventSubs[id] = ch
+	w.mux.Unlock()
+	return id, ch
+}
 
-		boardStenza := false
-		matchBoard := fmt.Sprintf("Vendor=0%x Product=0%x", nintendoVendor, wiiBoardProduct)
-		re := regexp.MustCompile("S: Sysfs=(.*)")
-		scanner := bufio.NewScanner(f)
+// unsubscribeEvents removes the channel registered under id.
+func (w *wiiBoard) unsubscribeEvents(id int) {
+	w.mux.Lock()
+	delete(w.eventSubs, id)
+	w.mux.Unlock()
+}
 
-		for scanner.Scan() {
-			t := scanner.Text()
-			if t == "" && boardStenza {
-				return errors.New("didn't find expected sys location in input device list file")
-			}
-			if strings.Contains(t, matchBoard) {
-				boardStenza = true
-			}
-			if !boardStenza {
-				continue
-			}
-			res := re.FindStringSubmatch(t)
-			if len(res) < 2 {
-				continue
-			}
-			m, err := filepath.Glob("/sys" + res[1] + "/device/power_supply/*/capacity")
-			if err != nil || len(m) != 1 {
-				return errors.New("didn't find expected battery capacity location")
-			}
-			batteryPath = m[0]
-			break
+// broadcastEvent fans e out to every subscribed channel. Don't block on a
+// slow subscriber; it'll simply miss this event.
+func (w *wiiBoard) broadcastEvent(e Event) {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	for _, ch := range w.eventSubs {
+		select {
+		case ch <- e:
+		default:
 		}
+	}
+}
 
-		if err := scanner.Err(); err != nil {
-			return errors.Wrapf(err, "error reading input device list file")
-		}
+func (w *wiiBoard) notifySensors(e Event) {
+	for _, h := range w.hooks {
+		h.OnSensors(e)
+	}
+}
 
-		w.conn = dev
-		w.batteryPath = batteryPath
-		return nil
+func (w *wiiBoard) notifyWeight(kg float64) {
+	for _, h := range w.hooks {
+		h.OnWeight(kg)
 	}
+}
 
-	return errors.New("Didn't find WiiBoard")
+func (w *wiiBoard) notifyCalibration(result string, settle time.Duration) {
+	for _, h := range w.hooks {
+		h.OnCalibration(result, settle)
+	}
+}
+
+// serialProvider is implemented by backends that can report a stable
+// hardware identifier for the connected board.
+type serialProvider interface {
+	Serial() string
+}
+
+// Detect enables picking first connected WiiBoard on the system. It tries
+// every known Backend in turn, keeps the first one that finds a board,
+// and loads any previously saved Calibration for it.
+func (w *wiiBoard) Detect() error {
+	var backend Backend
+	var err error
+	if backend, err = detectEvdev(); err != nil {
+		backend, err = DetectBluez()
+	}
+	if err != nil {
+		return errors.New("Didn't find WiiBoard")
+	}
+	w.backend = backend
+
+	if s, ok := backend.(serialProvider); ok {
+		w.boardSerial = s.Serial()
+		w.env = Env{BoardSerial: w.boardSerial}
+	}
+
+	if cal, err := loadCalibration(); err == nil {
+		if cal.BoardSerial != "" && cal.BoardSerial != w.boardSerial {
+			w.logger.Logf(w.env, "ignoring saved calibration for board %q: connected board is %q", cal.BoardSerial, w.boardSerial)
+		} else {
+			w.calibration = cal
+		}
+	}
+
+	return nil
 }
 
 // Listen start sending events on Events property of the board
 // Necessary before doing any operation, like calibrating
 func (w *wiiBoard) Listen() {
-	curEvent := Event{}
-	_ = curEvent
 	for {
-		events, err := w.conn.Read()
+		events, err := w.backend.Read()
 		if err != nil {
-			logrus.Error("Reading event error: %v", err)
+			w.logger.Logf(w.env, "Reading event error: %v", err)
 			// board disconnected, exit
 			os.Exit(0)
 		}
-		// logrus.Debugf("Got %d events, ranging...", len(events))
-		if len(events) < 5 {
-			// skip incomplete events
-			continue
-		}
-		for _, e := range events {
-			// logrus.Debug(e.String())
-			switch e.Type {
-			case evdev.EV_SYN:
-				w.mux.RLock()
-				if !w.calibrating {
-					// check for weights deviation, if deviation is big enough
-					// recalibrate and send new weight
-					if math.Abs(float64(curEvent.Total)-w.lastWeight)/w.lastWeight > 0.05 {
-						w.mux.RUnlock()
-						go w.sendMeanTotal()
-						curEvent = Event{}
-						continue
-					}
-
-					if curEvent.Total < 200 {
-						w.mux.RUnlock()
-						curEvent = Event{}
-						continue
-					}
-				}
-				w.mux.RUnlock()
 
-				// send current event and reset it.
-				// Don't block on sending if other side is slower than input events
-				select {
-				case w.events <- curEvent:
-				default:
-				}
-				curEvent = Event{}
-
-			// pressure point
-			case evdev.EV_ABS:
-				switch e.Code {
-				case evdev.ABS_HAT0Y:
-					curEvent.BottomRight = e.Value
-				case evdev.ABS_HAT1Y:
-					curEvent.BottomLeft = e.Value
-				case evdev.ABS_HAT0X:
-					curEvent.TopRight = e.Value
-				case evdev.ABS_HAT1X:
-					curEvent.TopLeft = e.Value
-				default:
-					if m, exists := evdev.ByEventType[int(e.Type)]; exists {
-						logrus.Infof("Unexpected event code: %s", m[int(e.Code)])
-					} else {
-						logrus.Infof("Unexpected unknown event code: %d", e.Code)
-					}
+		for _, curEvent := range events {
+			w.mux.RLock()
+			if !w.calibrating {
+				// check for weights deviation, if deviation is big enough
+				// recalibrate and send new weight. Compared against
+				// lastRawTotal, not lastWeight: once a Calibration is
+				// loaded lastWeight is in kilograms, while curEvent.Total
+				// stays a raw sensor sum, so the two aren't comparable.
+				if math.Abs(curEvent.Total-w.lastRawTotal)/w.lastRawTotal > 0.05 {
+					w.mux.RUnlock()
+					go w.sendMeanTotal()
 					continue
 				}
-				curEvent.Total = float64(curEvent.TopLeft + curEvent.TopRight + curEvent.BottomLeft + curEvent.BottomRight)
-			// main button
-			case evdev.EV_KEY:
-				if e.Code != 304 {
-					logrus.WithField("e", e).Infof("Unexpected event code: %d", e.Code)
+
+				if curEvent.Total < 200 {
+					w.mux.RUnlock()
 					continue
 				}
-				curEvent.Button = true
-			default:
-				logrus.WithField("e", e).Infof("Unexpected unknown event type: %d", e.Type)
 			}
+			w.mux.RUnlock()
+
+			w.notifySensors(curEvent)
+			w.broadcastEvent(curEvent)
 		}
 	}
 }
@@ -235,11 +222,16 @@ func (w *wiiBoard) sendMeanTotal() {
 	w.mux.RUnlock()
 	w.mux.Lock()
 	w.lastWeight = 0
+	w.lastRawTotal = 0
 	w.calibrating = true
 	w.mux.Unlock()
 
+	id, ch := w.subscribeEvents()
+	defer w.unsubscribeEvents(id)
+
 	// logrus.Debug("Calibrating...")
 	measureTime := time.Now().Add(3 * time.Second)
+	settleStart := time.Now()
 
 	var topLeft, topRight, bottomRight, bottomLeft int32
 	lastWeight := int32(0)
@@ -250,7 +242,7 @@ func (w *wiiBoard) sendMeanTotal() {
 			break
 		}
 		select {
-		case e := <-w.events:
+		case e := <-ch:
 			newWeight := e.TopLeft + e.TopRight + e.BottomRight + e.BottomLeft
 			// skips if one sensor sends 0, as we want an equilibrium state, we skip this invalid measure
 			if e.TopLeft == 0 || e.TopRight == 0 || e.BottomLeft == 0 || e.BottomRight == 0 {
@@ -266,6 +258,8 @@ func (w *wiiBoard) sendMeanTotal() {
 				n = 0
 				measureTime = time.Now().Add(3 * time.Second)
 				lastWeight = newWeight
+				settleStart = time.Now()
+				w.notifyCalibration("reset", 0)
 				continue
 			}
 
@@ -280,13 +274,20 @@ func (w *wiiBoard) sendMeanTotal() {
 			w.mux.Lock()
 			w.calibrating = false
 			w.mux.Unlock()
+			w.notifyCalibration("timeout", time.Since(settleStart))
 			return
 		}
 
 	}
 
 	w.mux.Lock()
-	w.lastWeight = float64((topLeft + topRight + bottomRight + bottomLeft) / n)
+	rawTotal := (topLeft + topRight + bottomRight + bottomLeft) / n
+	w.lastRawTotal = float64(rawTotal)
+	if w.calibration != nil {
+		w.lastWeight = w.calibration.apply(topLeft, topRight, bottomRight, bottomLeft, n)
+	} else {
+		w.lastWeight = float64(rawTotal)
+	}
 	w.calibrating = false
 	// logrus.Debugf("Calibrated! %.2f", w.lastWeight)
 
@@ -297,17 +298,12 @@ func (w *wiiBoard) sendMeanTotal() {
 	default:
 	}
 	w.mux.Unlock()
+
+	w.notifyCalibration("ok", time.Since(settleStart))
+	w.notifyWeight(w.lastWeight)
 }
 
 // Battery returns current power level
 func (w wiiBoard) Battery() (int, error) {
-	b, err := ioutil.ReadFile(w.batteryPath)
-	if err != nil {
-		return 0, errors.Wrap(err, "couldn't read from board battery file")
-	}
-	battery, err := strconv.Atoi(strings.TrimSpace(string(b)))
-	if err != nil {
-		return 0, errors.Wrap(err, "didn't find an integer in battery capacity file")
-	}
-	return battery, nil
+	return w.backend.Battery()
 }